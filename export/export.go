@@ -0,0 +1,146 @@
+// Package export writes PrimeGapsInfo data in the formats OEIS expects
+// for prime-gap sequences (A001223, the gaps themselves; A000230, the
+// first prime before each gap's first occurrence), plus a plain
+// histogram CSV for other analysis. The streaming formats (b-file,
+// first-occurrence) are driven by PrimeGapsInfo.Sink rather than
+// GapCounter, since GapCounter only tracks counts per gap size and
+// throws away the order primes were found in.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BFileSink returns a Sink (for PrimeGapsInfo.Sink) that writes one
+// "n g_n" line per prime to w, in OEIS b-file format: n is a 1-based
+// index starting at startIndex+1, incrementing once per call, and g_n
+// is the gap passed to that call. w is buffered; flush must be called
+// once iteration finishes to ensure everything reaches w.
+//
+// startIndex should be the number of lines already written for this
+// sequence (0 for a fresh file, or PrimeGapsInfo.PrimesSoFar-1 when
+// continuing a run whose info.json already reflects earlier primes).
+func BFileSink(w io.Writer, startIndex uint64) (sink func(prime *big.Int, gap uint64), flush func() error) {
+	bw := bufio.NewWriter(w)
+	n := startIndex
+	sink = func(prime *big.Int, gap uint64) {
+		n++
+		fmt.Fprintf(bw, "%d %d\n", n, gap)
+	}
+	return sink, bw.Flush
+}
+
+// FirstOccurrence returns a Sink that records, for each gap size g seen,
+// the first prime p such that the gap immediately after p equals g —
+// the shape OEIS A000230 wants. prevPrime seeds the "previous prime"
+// the first call's gap is measured from; pass whatever PrimeGapsInfo's
+// LastPrime is at the moment the Sink is installed. Call results once
+// iteration finishes to read the accumulated map.
+func FirstOccurrence(prevPrime *big.Int) (sink func(prime *big.Int, gap uint64), results func() map[uint64]*big.Int) {
+	seen := make(map[uint64]*big.Int)
+	prev := new(big.Int).Set(prevPrime)
+
+	sink = func(prime *big.Int, gap uint64) {
+		if _, ok := seen[gap]; !ok {
+			seen[gap] = new(big.Int).Set(prev)
+		}
+		prev.Set(prime)
+	}
+	results = func() map[uint64]*big.Int { return seen }
+	return sink, results
+}
+
+// Combine returns a Sink that calls each of sinks, in order, for every
+// prime, so multiple exports (e.g. a b-file and first-occurrence
+// tracking) can share a single IterateTo/IterateToParallel pass instead
+// of each requiring their own. Nil sinks are skipped.
+func Combine(sinks ...func(prime *big.Int, gap uint64)) func(prime *big.Int, gap uint64) {
+	return func(prime *big.Int, gap uint64) {
+		for _, s := range sinks {
+			if s != nil {
+				s(prime, gap)
+			}
+		}
+	}
+}
+
+// WriteHistogramCSV writes gapCounter (PrimeGapsInfo.GapCounter) as
+// "gap,count" rows, one per nonzero entry, in increasing gap order.
+// gapCounter is indexed by gap/2, matching the rest of this codebase.
+func WriteHistogramCSV(w io.Writer, gapCounter []uint64) error {
+	bw := bufio.NewWriter(w)
+	for idx, count := range gapCounter {
+		if count == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(bw, "%d,%d\n", idx*2, count); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFirstOccurrenceCSV writes the map produced by FirstOccurrence's
+// results func as "gap,prime" rows, in increasing gap order.
+func WriteFirstOccurrenceCSV(w io.Writer, firstOccurrence map[uint64]*big.Int) error {
+	gaps := make([]uint64, 0, len(firstOccurrence))
+	for g := range firstOccurrence {
+		gaps = append(gaps, g)
+	}
+	sort.Slice(gaps, func(a, b int) bool { return gaps[a] < gaps[b] })
+
+	bw := bufio.NewWriter(w)
+	for _, g := range gaps {
+		if _, err := fmt.Fprintf(bw, "%d,%s\n", g, firstOccurrence[g].Text(10)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ResumeFromBFile reads a b-file as written by BFileSink and reconstructs
+// the LastPrime and PrimesSoFar a PrimeGapsInfo would have had right
+// after writing it, so a long run can be checkpointed to a b-file alone
+// and resumed later without info.json staying in lockstep. As elsewhere
+// in this codebase, the sequence is assumed to start at the prime 3
+// (PrimesSoFar 1); 2 itself is never tracked.
+func ResumeFromBFile(r io.Reader) (lastPrime *big.Int, primesSoFar uint64, err error) {
+	lastPrime = big.NewInt(3)
+	primesSoFar = 1
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, 0, fmt.Errorf("malformed b-file line %q", line)
+		}
+
+		n, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed b-file line %q: %w", line, err)
+		}
+		gap, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed b-file line %q: %w", line, err)
+		}
+
+		lastPrime.Add(lastPrime, new(big.Int).SetUint64(gap))
+		primesSoFar = n + 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return lastPrime, primesSoFar, nil
+}
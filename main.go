@@ -1,423 +1,807 @@
-package main
-
-import (
-	"encoding/json"
-	"io/ioutil"
-	"log"
-	"math"
-	"math/big"
-	"os"
-	"runtime"
-	"strconv"
-	"time"
-)
-
-// The information we are creating.
-type PrimeGapsInfo struct {
-	// LastPrime is the last prime number that we saw.
-	LastPrime *big.Int
-
-	// CurrentNumber is strictly larger than LastPrime and
-	// is the next number to check if it is prime. All numbers
-	// between LastPrime and CurrentNumber, excluding CurrentNumber,
-	// must be (probably) composite.
-	CurrentNumber *big.Int
-
-	// The counters for gaps. Index 0 should always be empty. Index 1
-	// is the number of prime number gaps of distance 2, e.g., 3 and 5.
-	// Note that we should always start CurrentNumber at 3 or higher.
-	GapCounter []uint64
-
-	// The number of primes we've encountered so far.
-	PrimesSoFar uint64
-
-	// MillerRabinSeeds should be negative to check for primes deterministically,
-	// and any non-negative number to check for primes probabilistically using
-	// the Miller-Rabin test with the given value for n and a Baillie-PSW test.
-	// More bases reduces the number of false positives. Note that even with a
-	// value of 0, this is perfectly accurate for CurrentNumber below 2^64.
-	MillerRabinBases int
-
-	// Only used when checking primes deterministically, such as when parallelizing
-	// and determinism is important, or when MillerRabinBases is 0. The precomputed
-	// prime numbers to speed up the deterministic prime check, starting with index
-	// 0 = 2, index 1 = 3.
-	//
-	// First 10 million primes is a good target for large sweeps. You can google
-	// "10 millionth prime", square it, and that's approximately how large a number
-	// whose primality check is improved using the precomputed primes.
-	PrecomputedPrimes []uint32
-}
-
-// PrecomputePrimes ensures that we have precomputed at least the given number of
-// primes. Only works for primes below 2^32. 10 million is a good number
-// Precompute these small primes for faster checks on larger primes.
-func (i *PrimeGapsInfo) PrecomputePrimes(numberOfPrimes int) {
-	var tmp *big.Int
-	if i.PrecomputedPrimes == nil {
-		i.PrecomputedPrimes = make([]uint32, 0, numberOfPrimes)
-		tmp = big.NewInt(2)
-	} else {
-		tmp = big.NewInt(int64(i.PrecomputedPrimes[len(i.PrecomputedPrimes)-1] + 2))
-	}
-
-	one := big.NewInt(1)
-	lastPrintedProgress := time.Now()
-
-	log.Println("Precomputing primes...")
-	for len(i.PrecomputedPrimes) < numberOfPrimes {
-		if tmp.ProbablyPrime(0) { // deterministic for numbers this small
-			i.PrecomputedPrimes = append(i.PrecomputedPrimes, uint32(tmp.Int64()))
-		}
-		tmp.Add(tmp, one)
-
-		if time.Since(lastPrintedProgress) > 5*time.Second {
-			log.Printf("Precomputing primes... %d", len(i.PrecomputedPrimes))
-			lastPrintedProgress = time.Now()
-		}
-	}
-	log.Printf("Finished precomputing the first %d primes", len(i.PrecomputedPrimes))
-}
-
-func (i *PrimeGapsInfo) IterateTo(targetNumberOfPrimes uint64) {
-	two := big.NewInt(2)
-	gapBig := big.NewInt(0)
-	lastPrint := time.Now()
-	var gapIndex int
-
-	if i.MillerRabinBases >= 0 {
-		for i.PrimesSoFar < targetNumberOfPrimes {
-			if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
-				gapBig.Neg(i.LastPrime)
-				gapBig.Add(gapBig, i.CurrentNumber)
-				gapIndex = int(gapBig.Uint64()) / 2
-				for gapIndex >= len(i.GapCounter) {
-					i.ExpandGapCounter()
-				}
-				i.GapCounter[gapIndex]++
-				i.PrimesSoFar++
-				i.LastPrime.Set(i.CurrentNumber)
-
-				if time.Since(lastPrint) > time.Second*30 {
-					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
-					lastPrint = time.Now()
-				}
-			}
-
-			i.CurrentNumber.Add(i.CurrentNumber, two)
-		}
-	} else {
-		space := make([]big.Int, 3)
-		zero := big.NewInt(0)
-		for i.PrimesSoFar < targetNumberOfPrimes {
-			if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
-				gapBig.Neg(i.LastPrime)
-				gapBig.Add(gapBig, i.CurrentNumber)
-				gapIndex = int(gapBig.Uint64()) / 2
-				for gapIndex >= len(i.GapCounter) {
-					i.ExpandGapCounter()
-				}
-				i.GapCounter[gapIndex]++
-				i.PrimesSoFar++
-				i.LastPrime.Set(i.CurrentNumber)
-
-				if time.Since(lastPrint) > time.Second*30 {
-					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
-					lastPrint = time.Now()
-				}
-			}
-
-			i.CurrentNumber.Add(i.CurrentNumber, two)
-		}
-	}
-}
-
-func (i *PrimeGapsInfo) IterateToNumber(targetNumber *big.Int) {
-	two := big.NewInt(2)
-	gapBig := big.NewInt(0)
-	lastPrint := time.Now()
-	var gapIndex int
-
-	if i.MillerRabinBases >= 0 {
-		for i.CurrentNumber.Cmp(targetNumber) < 0 {
-			if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
-				gapBig.Neg(i.LastPrime)
-				gapBig.Add(gapBig, i.CurrentNumber)
-				gapIndex = int(gapBig.Uint64()) / 2
-				for gapIndex >= len(i.GapCounter) {
-					i.ExpandGapCounter()
-				}
-				i.GapCounter[gapIndex]++
-				i.PrimesSoFar++
-				i.LastPrime.Set(i.CurrentNumber)
-
-				if time.Since(lastPrint) > time.Second*30 {
-					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
-					lastPrint = time.Now()
-				}
-			}
-
-			i.CurrentNumber.Add(i.CurrentNumber, two)
-		}
-	} else {
-		space := make([]big.Int, 3)
-		zero := big.NewInt(0)
-		for i.CurrentNumber.Cmp(targetNumber) < 0 {
-			if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
-				gapBig.Neg(i.LastPrime)
-				gapBig.Add(gapBig, i.CurrentNumber)
-				gapIndex = int(gapBig.Uint64()) / 2
-				for gapIndex >= len(i.GapCounter) {
-					i.ExpandGapCounter()
-				}
-				i.GapCounter[gapIndex]++
-				i.PrimesSoFar++
-				i.LastPrime.Set(i.CurrentNumber)
-
-				if time.Since(lastPrint) > time.Second*30 {
-					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
-					lastPrint = time.Now()
-				}
-			}
-
-			i.CurrentNumber.Add(i.CurrentNumber, two)
-		}
-	}
-}
-
-func (i *PrimeGapsInfo) IterateToParallel(targetNumberOfPrimes uint64, parallelism int, blockSize uint64) {
-	if targetNumberOfPrimes <= i.PrimesSoFar {
-		return
-	}
-
-	if blockSize%2 != 0 {
-		log.Fatalf("blockSize must be even")
-	}
-
-	blockSizeBig := big.NewInt(int64(blockSize))
-	for {
-		blocks := 1
-
-		expectedNumberOfPrimesAdjustment := i.PrimesSoFar - approxPrimesBelow(i.CurrentNumber)
-
-		endNumber := big.NewInt(0)
-		endNumber.Add(i.CurrentNumber, blockSizeBig)
-
-		endNumberIfOneMoreBlock := big.NewInt(0)
-		endNumberIfOneMoreBlock.Add(endNumber, blockSizeBig)
-
-		expectedNumberOfPrimes := approxPrimesBelow(endNumber) + expectedNumberOfPrimesAdjustment
-		expectedNumberOfPrimesIfOneMoreBlock := approxPrimesBelow(endNumberIfOneMoreBlock) + expectedNumberOfPrimesAdjustment
-
-		for expectedNumberOfPrimesIfOneMoreBlock < targetNumberOfPrimes-50_000 {
-			blocks++
-			endNumber.Set(endNumberIfOneMoreBlock)
-			expectedNumberOfPrimes = expectedNumberOfPrimesIfOneMoreBlock
-
-			endNumberIfOneMoreBlock.Add(endNumberIfOneMoreBlock, blockSizeBig)
-			expectedNumberOfPrimesIfOneMoreBlock = approxPrimesBelow(endNumberIfOneMoreBlock) + expectedNumberOfPrimesAdjustment
-
-			if blocks >= parallelism {
-				break
-			}
-		}
-
-		if blocks < 2 {
-			break
-		}
-
-		if blocks > parallelism {
-			blocks = parallelism
-		}
-		log.Printf(
-			"Running %d blocks of size %d to go from %d primes to about %d",
-			blocks, blockSize, i.PrimesSoFar, expectedNumberOfPrimes,
-		)
-
-		channels := make([]chan uint64, blocks)
-		for j := 0; j < blocks; j++ {
-			channels[j] = make(chan uint64)
-		}
-
-		finalInfoChannel := make(chan big.Int)
-
-		for j := 0; j < blocks; j++ {
-			jthBlockStartsAt := big.NewInt(0)
-			jthBlockStartsAt.Add(i.CurrentNumber, big.NewInt(int64(blockSize)*int64(j)))
-
-			jthBlockEndsAt := big.NewInt(0)
-			jthBlockEndsAt.Add(jthBlockStartsAt, big.NewInt(int64(blockSize)))
-
-			incrementUntilDeterministicallyPrime(jthBlockStartsAt, i.PrecomputedPrimes)
-			incrementUntilDeterministicallyPrime(jthBlockEndsAt, i.PrecomputedPrimes)
-
-			go func(blockIndex int, blockStartsAt *big.Int, blockEndsAt *big.Int) {
-				myInfo := PrimeGapsInfo{
-					LastPrime:         blockStartsAt,
-					CurrentNumber:     big.NewInt(0).Add(blockStartsAt, big.NewInt(2)),
-					GapCounter:        make([]uint64, len(i.GapCounter)),
-					PrimesSoFar:       1,
-					MillerRabinBases:  i.MillerRabinBases,
-					PrecomputedPrimes: i.PrecomputedPrimes,
-				}
-				myInfo.IterateToNumber(blockEndsAt)
-				for k := 0; k < len(myInfo.GapCounter); k++ {
-					channels[blockIndex] <- myInfo.GapCounter[k]
-				}
-				channels[blockIndex] <- math.MaxUint64
-				channels[blockIndex] <- myInfo.PrimesSoFar
-
-				if blockIndex == blocks-1 {
-					finalInfoChannel <- *myInfo.LastPrime
-					finalInfoChannel <- *myInfo.CurrentNumber
-				}
-			}(j, jthBlockStartsAt, jthBlockEndsAt)
-		}
-
-		for j := 0; j < blocks; j++ {
-			nextGapValue := <-channels[j]
-			for k := 0; nextGapValue != math.MaxUint64; k++ {
-				if k >= len(i.GapCounter) {
-					i.ExpandGapCounter()
-				}
-				i.GapCounter[k] += nextGapValue
-				nextGapValue = <-channels[j]
-			}
-			i.PrimesSoFar += <-channels[j]
-		}
-
-		i.LastPrime = big.NewInt(0)
-		i.CurrentNumber = big.NewInt(0)
-		*i.LastPrime = <-finalInfoChannel
-		*i.CurrentNumber = <-finalInfoChannel
-
-		log.Printf(
-			"After running blocks, now have %d primes (last prime: %s, current number: %s)",
-			i.PrimesSoFar, i.LastPrime.Text(10), i.CurrentNumber.Text(10),
-		)
-	}
-
-	log.Printf(
-		"Finding the remaining primes (at %d, want %d) serially",
-		i.PrimesSoFar, targetNumberOfPrimes,
-	)
-	i.IterateTo(targetNumberOfPrimes)
-}
-
-func approxPrimesBelow(n *big.Int) uint64 {
-	// Prime Number Theorem: number of primes below x ~= x/ln(x).
-
-	approxN, _ := big.NewFloat(0).SetInt(n).Float64()
-	approxLogN := int64(math.Log(approxN))
-
-	result := big.NewInt(0).Set(n)
-	result.Div(result, big.NewInt(approxLogN))
-	return result.Uint64()
-}
-
-func (i *PrimeGapsInfo) ExpandGapCounter() {
-	newGapCounter := make([]uint64, len(i.GapCounter)*2)
-	for j := 0; j < len(i.GapCounter); j++ {
-		newGapCounter[j] = i.GapCounter[j]
-	}
-	i.GapCounter = newGapCounter
-}
-
-var precomputeWarning bool = false
-
-func deterministicIsPrime(n *big.Int, space []big.Int, precomputedPrimes []uint32, zero *big.Int) bool {
-	(&space[0]).Sqrt(n) // space[0] = stopping point
-
-	for precomputedPrimesIndex := 0; precomputedPrimesIndex < len(precomputedPrimes); precomputedPrimesIndex++ {
-		(&space[1]).SetUint64(uint64(precomputedPrimes[precomputedPrimesIndex])) // space[1] = current prime
-		if (&space[2]).Rem(n, &space[1]).Cmp(zero) == 0 {                        // (space[2] = (n % space[1])) == 0
-			return false
-		}
-		if (&space[1]).Cmp(&space[0]) > 0 {
-			return true
-		}
-	}
-
-	// fallback, no more precomputed primes :(
-	if !precomputeWarning {
-		log.Printf("Ran out of precomputed primes checking if %s is prime deterministically", n.Text(10))
-		precomputeWarning = true
-	}
-
-	two := big.NewInt(2)
-	for (&space[1]).Cmp(&space[0]) <= 0 {
-		(&space[1]).Add(&space[1], two)
-		if (&space[2]).Rem(n, &space[1]).Cmp(zero) == 0 {
-			return false
-		}
-	}
-
-	return true
-}
-
-func incrementUntilDeterministicallyPrime(n *big.Int, precomputedPrimes []uint32) {
-	zero := big.NewInt(0)
-	one := big.NewInt(1)
-	space := make([]big.Int, 3)
-
-	for !deterministicIsPrime(n, space, precomputedPrimes, zero) {
-		n.Add(n, one)
-	}
-}
-
-func getIntEnviron(envName string, def int) int {
-	envVal, found := os.LookupEnv(envName)
-	if !found {
-		log.Printf("Missing environment variable %s, assuming %d", envName, def)
-		return def
-	}
-
-	parsed, err := strconv.Atoi(envVal)
-	if err != nil {
-		log.Fatalf("Error interpreting environment variable %s: %s", envName, err)
-	}
-	return parsed
-}
-
-func main() {
-	info := &PrimeGapsInfo{
-		LastPrime:        big.NewInt(3),
-		CurrentNumber:    big.NewInt(5),
-		GapCounter:       make([]uint64, 512),
-		PrimesSoFar:      1,
-		MillerRabinBases: 10,
-	}
-
-	var err error
-	var marshalled []byte
-	marshalled, err = ioutil.ReadFile("info.json")
-	if err == nil {
-		err = json.Unmarshal(marshalled, &info)
-		if err != nil {
-			log.Fatalf("error unmarshalling info.json: %s", err)
-		}
-	} else if !os.IsNotExist(err) {
-		log.Fatalf("error opening info.json: %s", err)
-	}
-
-	targetNumberOfPrimesToPrecompute := getIntEnviron("PRECOMPUTE_PRIMES", 1_000_000)
-	targetNumberOfPrimesForPlot := getIntEnviron("TARGET_PRIMES", int(info.PrimesSoFar)+10_000_000)
-	parallelism := getIntEnviron("PARALLELISM", runtime.NumCPU())
-	blockSize := getIntEnviron("BLOCK_SIZE", 1_000_000)
-
-	info.PrecomputePrimes(targetNumberOfPrimesToPrecompute)
-
-	log.Printf("Continuing from %d primes...", info.PrimesSoFar)
-	info.IterateToParallel(uint64(targetNumberOfPrimesForPlot), parallelism, uint64(blockSize))
-	log.Printf("Now at %d primes", info.PrimesSoFar)
-
-	marshalled, err = json.Marshal(info)
-	if err != nil {
-		log.Fatalf("error marshalling result: %s", err)
-	}
-
-	err = ioutil.WriteFile("info.json", marshalled, 0644)
-	if err != nil {
-		log.Fatalf("error closing file: %s", err)
-	}
-}
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/Tjstretchalot/prime-gaps/export"
+	"github.com/Tjstretchalot/prime-gaps/primebits"
+	"github.com/Tjstretchalot/prime-gaps/primecount"
+	"github.com/Tjstretchalot/prime-gaps/sieve"
+	"github.com/Tjstretchalot/prime-gaps/wheel"
+)
+
+// maxSievableUint64 is the largest candidate the segmented sieve is
+// allowed to produce, leaving enough headroom below 2^64 that a
+// segment never wraps around. Anything past this falls back to the
+// big.Int trial-division path below, which is the only part of this
+// file that still works past 2^64.
+const maxSievableUint64 = math.MaxUint64 - 2*sieve.DefaultWidth
+
+// wheelFloor is the smallest number the mod-210 wheel can represent
+// (see the wheel package): 2, 3, 5, and 7 themselves aren't reachable
+// on it, so candidates below this are still stepped by two.
+var wheelFloor = big.NewInt(11)
+
+// The information we are creating.
+type PrimeGapsInfo struct {
+	// LastPrime is the last prime number that we saw.
+	LastPrime *big.Int
+
+	// CurrentNumber is strictly larger than LastPrime and
+	// is the next number to check if it is prime. All numbers
+	// between LastPrime and CurrentNumber, excluding CurrentNumber,
+	// must be (probably) composite.
+	CurrentNumber *big.Int
+
+	// The counters for gaps. Index 0 should always be empty. Index 1
+	// is the number of prime number gaps of distance 2, e.g., 3 and 5.
+	// Note that we should always start CurrentNumber at 3 or higher.
+	GapCounter []uint64
+
+	// The number of primes we've encountered so far.
+	PrimesSoFar uint64
+
+	// MillerRabinSeeds should be negative to check for primes deterministically,
+	// and any non-negative number to check for primes probabilistically using
+	// the Miller-Rabin test with the given value for n and a Baillie-PSW test.
+	// More bases reduces the number of false positives. Note that even with a
+	// value of 0, this is perfectly accurate for CurrentNumber below 2^64.
+	MillerRabinBases int
+
+	// Only used when checking primes deterministically, such as when parallelizing
+	// and determinism is important, or when MillerRabinBases is 0. The precomputed
+	// prime numbers to speed up the deterministic prime check, stored as a
+	// bit-packed set rather than a []uint32 list (~3-4x smaller for the first
+	// 10 million primes: one bit per odd number covered, regardless of how
+	// many of them are prime).
+	//
+	// First 10 million primes is a good target for large sweeps. You can google
+	// "10 millionth prime", square it, and that's approximately how large a number
+	// whose primality check is improved using the precomputed primes.
+	PrecomputedPrimes *primebits.Set
+
+	// Sink, if non-nil, is called once per prime found, in increasing
+	// order, with the prime itself and the gap since the previous one
+	// (see the export package for uses, e.g. streaming an OEIS b-file).
+	// prime is only valid for the duration of the call; Sink must copy
+	// it (e.g. new(big.Int).Set(prime)) if it needs to keep it around.
+	Sink func(prime *big.Int, gap uint64) `json:"-"`
+}
+
+// primeGapEntry is one entry of a block's buffered Sink stream inside
+// IterateToParallel: a prime and the gap leading into it, captured by
+// value (prime is a copy, not an alias into big.Int scratch space) so it
+// survives until the seam-merge stage replays it to the top-level Sink.
+type primeGapEntry struct {
+	prime big.Int
+	gap   uint64
+}
+
+// PrecomputePrimes ensures that we have precomputed at least the given number of
+// primes. Only works for primes below 2^32. 10 million is a good number
+// Precompute these small primes for faster checks on larger primes.
+func (i *PrimeGapsInfo) PrecomputePrimes(numberOfPrimes int) {
+	if i.PrecomputedPrimes == nil {
+		i.PrecomputedPrimes = primebits.NewSet()
+	}
+
+	bound := i.PrecomputedPrimes.Bound()
+	if bound < 1<<16 {
+		bound = 1 << 16
+	}
+
+	lastPrintedProgress := time.Now()
+
+	log.Println("Precomputing primes...")
+	for i.PrecomputedPrimes.Count() < numberOfPrimes {
+		bound *= 2
+		i.PrecomputedPrimes.GrowTo(bound)
+
+		if time.Since(lastPrintedProgress) > 5*time.Second {
+			log.Printf("Precomputing primes... %d", i.PrecomputedPrimes.Count())
+			lastPrintedProgress = time.Now()
+		}
+	}
+	log.Printf("Finished precomputing the first %d primes", i.PrecomputedPrimes.Count())
+}
+
+// sieveDeterministically drains primes from a segmented sieve seeded at
+// i.CurrentNumber, folding each one into GapCounter/PrimesSoFar exactly
+// as the trial-division loops below do. It stops, without consuming,
+// at the first candidate accept rejects, leaving i.CurrentNumber there
+// so the trial-division fallback resumes from the exact boundary. Only
+// usable while i.CurrentNumber fits in a uint64; no-op otherwise.
+func (i *PrimeGapsInfo) sieveDeterministically(accept func(candidate uint64) bool) {
+	if !i.CurrentNumber.IsUint64() {
+		return
+	}
+
+	if i.PrecomputedPrimes == nil {
+		i.PrecomputedPrimes = primebits.NewSet()
+	}
+	s := sieve.NewSegmentedSieve(i.CurrentNumber.Uint64(), i.PrecomputedPrimes, 0)
+	last := i.LastPrime.Uint64()
+	next := i.CurrentNumber.Uint64()
+	lastPrint := time.Now()
+
+	for {
+		candidate := s.Next()
+		if !accept(candidate) {
+			next = candidate
+			break
+		}
+
+		gap := candidate - last
+		gapIndex := int(gap) / 2
+		for gapIndex >= len(i.GapCounter) {
+			i.ExpandGapCounter()
+		}
+		i.GapCounter[gapIndex]++
+		i.PrimesSoFar++
+		if i.Sink != nil {
+			i.Sink(new(big.Int).SetUint64(candidate), gap)
+		}
+		last = candidate
+		next = candidate + 2
+
+		if time.Since(lastPrint) > time.Second*30 {
+			log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+			lastPrint = time.Now()
+		}
+	}
+
+	i.LastPrime.SetUint64(last)
+	i.CurrentNumber.SetUint64(next)
+}
+
+func (i *PrimeGapsInfo) IterateTo(targetNumberOfPrimes uint64) {
+	two := big.NewInt(2)
+	gapBig := big.NewInt(0)
+	lastPrint := time.Now()
+	var gapIndex int
+
+	if i.MillerRabinBases >= 0 {
+		for i.PrimesSoFar < targetNumberOfPrimes && i.CurrentNumber.Cmp(wheelFloor) < 0 {
+			if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
+				gapBig.Neg(i.LastPrime)
+				gapBig.Add(gapBig, i.CurrentNumber)
+				gapIndex = int(gapBig.Uint64()) / 2
+				for gapIndex >= len(i.GapCounter) {
+					i.ExpandGapCounter()
+				}
+				i.GapCounter[gapIndex]++
+				i.PrimesSoFar++
+				i.LastPrime.Set(i.CurrentNumber)
+				if i.Sink != nil {
+					i.Sink(i.CurrentNumber, gapBig.Uint64())
+				}
+
+				if time.Since(lastPrint) > time.Second*30 {
+					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+					lastPrint = time.Now()
+				}
+			}
+
+			i.CurrentNumber.Add(i.CurrentNumber, two)
+		}
+
+		if i.PrimesSoFar < targetNumberOfPrimes {
+			it := wheel.NewIterator(i.CurrentNumber)
+			for i.PrimesSoFar < targetNumberOfPrimes {
+				if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
+					gapBig.Neg(i.LastPrime)
+					gapBig.Add(gapBig, i.CurrentNumber)
+					gapIndex = int(gapBig.Uint64()) / 2
+					for gapIndex >= len(i.GapCounter) {
+						i.ExpandGapCounter()
+					}
+					i.GapCounter[gapIndex]++
+					i.PrimesSoFar++
+					i.LastPrime.Set(i.CurrentNumber)
+					if i.Sink != nil {
+						i.Sink(i.CurrentNumber, gapBig.Uint64())
+					}
+
+					if time.Since(lastPrint) > time.Second*30 {
+						log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+						lastPrint = time.Now()
+					}
+				}
+
+				it.Advance(i.CurrentNumber)
+			}
+		}
+	} else {
+		i.sieveDeterministically(func(candidate uint64) bool {
+			return i.PrimesSoFar < targetNumberOfPrimes && candidate < maxSievableUint64
+		})
+
+		space := make([]big.Int, 3)
+		zero := big.NewInt(0)
+		for i.PrimesSoFar < targetNumberOfPrimes && i.CurrentNumber.Cmp(wheelFloor) < 0 {
+			if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
+				gapBig.Neg(i.LastPrime)
+				gapBig.Add(gapBig, i.CurrentNumber)
+				gapIndex = int(gapBig.Uint64()) / 2
+				for gapIndex >= len(i.GapCounter) {
+					i.ExpandGapCounter()
+				}
+				i.GapCounter[gapIndex]++
+				i.PrimesSoFar++
+				i.LastPrime.Set(i.CurrentNumber)
+				if i.Sink != nil {
+					i.Sink(i.CurrentNumber, gapBig.Uint64())
+				}
+
+				if time.Since(lastPrint) > time.Second*30 {
+					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+					lastPrint = time.Now()
+				}
+			}
+
+			i.CurrentNumber.Add(i.CurrentNumber, two)
+		}
+
+		if i.PrimesSoFar < targetNumberOfPrimes {
+			it := wheel.NewIterator(i.CurrentNumber)
+			for i.PrimesSoFar < targetNumberOfPrimes {
+				if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
+					gapBig.Neg(i.LastPrime)
+					gapBig.Add(gapBig, i.CurrentNumber)
+					gapIndex = int(gapBig.Uint64()) / 2
+					for gapIndex >= len(i.GapCounter) {
+						i.ExpandGapCounter()
+					}
+					i.GapCounter[gapIndex]++
+					i.PrimesSoFar++
+					i.LastPrime.Set(i.CurrentNumber)
+					if i.Sink != nil {
+						i.Sink(i.CurrentNumber, gapBig.Uint64())
+					}
+
+					if time.Since(lastPrint) > time.Second*30 {
+						log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+						lastPrint = time.Now()
+					}
+				}
+
+				it.Advance(i.CurrentNumber)
+			}
+		}
+	}
+}
+
+func (i *PrimeGapsInfo) IterateToNumber(targetNumber *big.Int) {
+	two := big.NewInt(2)
+	gapBig := big.NewInt(0)
+	lastPrint := time.Now()
+	var gapIndex int
+
+	if i.MillerRabinBases >= 0 {
+		for i.CurrentNumber.Cmp(targetNumber) < 0 && i.CurrentNumber.Cmp(wheelFloor) < 0 {
+			if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
+				gapBig.Neg(i.LastPrime)
+				gapBig.Add(gapBig, i.CurrentNumber)
+				gapIndex = int(gapBig.Uint64()) / 2
+				for gapIndex >= len(i.GapCounter) {
+					i.ExpandGapCounter()
+				}
+				i.GapCounter[gapIndex]++
+				i.PrimesSoFar++
+				i.LastPrime.Set(i.CurrentNumber)
+				if i.Sink != nil {
+					i.Sink(i.CurrentNumber, gapBig.Uint64())
+				}
+
+				if time.Since(lastPrint) > time.Second*30 {
+					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+					lastPrint = time.Now()
+				}
+			}
+
+			i.CurrentNumber.Add(i.CurrentNumber, two)
+		}
+
+		if i.CurrentNumber.Cmp(targetNumber) < 0 {
+			it := wheel.NewIterator(i.CurrentNumber)
+			for i.CurrentNumber.Cmp(targetNumber) < 0 {
+				if i.CurrentNumber.ProbablyPrime(i.MillerRabinBases) {
+					gapBig.Neg(i.LastPrime)
+					gapBig.Add(gapBig, i.CurrentNumber)
+					gapIndex = int(gapBig.Uint64()) / 2
+					for gapIndex >= len(i.GapCounter) {
+						i.ExpandGapCounter()
+					}
+					i.GapCounter[gapIndex]++
+					i.PrimesSoFar++
+					i.LastPrime.Set(i.CurrentNumber)
+					if i.Sink != nil {
+						i.Sink(i.CurrentNumber, gapBig.Uint64())
+					}
+
+					if time.Since(lastPrint) > time.Second*30 {
+						log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+						lastPrint = time.Now()
+					}
+				}
+
+				it.Advance(i.CurrentNumber)
+			}
+		}
+	} else {
+		sieveBound := uint64(maxSievableUint64)
+		if targetNumber.IsUint64() && targetNumber.Uint64() < sieveBound {
+			sieveBound = targetNumber.Uint64()
+		}
+		i.sieveDeterministically(func(candidate uint64) bool {
+			return candidate < sieveBound
+		})
+
+		space := make([]big.Int, 3)
+		zero := big.NewInt(0)
+		for i.CurrentNumber.Cmp(targetNumber) < 0 && i.CurrentNumber.Cmp(wheelFloor) < 0 {
+			if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
+				gapBig.Neg(i.LastPrime)
+				gapBig.Add(gapBig, i.CurrentNumber)
+				gapIndex = int(gapBig.Uint64()) / 2
+				for gapIndex >= len(i.GapCounter) {
+					i.ExpandGapCounter()
+				}
+				i.GapCounter[gapIndex]++
+				i.PrimesSoFar++
+				i.LastPrime.Set(i.CurrentNumber)
+				if i.Sink != nil {
+					i.Sink(i.CurrentNumber, gapBig.Uint64())
+				}
+
+				if time.Since(lastPrint) > time.Second*30 {
+					log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+					lastPrint = time.Now()
+				}
+			}
+
+			i.CurrentNumber.Add(i.CurrentNumber, two)
+		}
+
+		if i.CurrentNumber.Cmp(targetNumber) < 0 {
+			it := wheel.NewIterator(i.CurrentNumber)
+			for i.CurrentNumber.Cmp(targetNumber) < 0 {
+				if deterministicIsPrime(i.CurrentNumber, space, i.PrecomputedPrimes, zero) {
+					gapBig.Neg(i.LastPrime)
+					gapBig.Add(gapBig, i.CurrentNumber)
+					gapIndex = int(gapBig.Uint64()) / 2
+					for gapIndex >= len(i.GapCounter) {
+						i.ExpandGapCounter()
+					}
+					i.GapCounter[gapIndex]++
+					i.PrimesSoFar++
+					i.LastPrime.Set(i.CurrentNumber)
+					if i.Sink != nil {
+						i.Sink(i.CurrentNumber, gapBig.Uint64())
+					}
+
+					if time.Since(lastPrint) > time.Second*30 {
+						log.Printf("Calculated %d primes so far..", i.PrimesSoFar)
+						lastPrint = time.Now()
+					}
+				}
+
+				it.Advance(i.CurrentNumber)
+			}
+		}
+	}
+}
+
+func (i *PrimeGapsInfo) IterateToParallel(targetNumberOfPrimes uint64, parallelism int, blockSize uint64) {
+	if targetNumberOfPrimes <= i.PrimesSoFar {
+		return
+	}
+
+	if blockSize%2 != 0 {
+		log.Fatalf("blockSize must be even")
+	}
+
+	blockSizeBig := big.NewInt(int64(blockSize))
+	for {
+		blocks := 1
+
+		expectedNumberOfPrimesAdjustment := i.PrimesSoFar - uint64(primecount.EstimatePrimesBelow(i.CurrentNumber))
+
+		endNumber := big.NewInt(0)
+		endNumber.Add(i.CurrentNumber, blockSizeBig)
+
+		endNumberIfOneMoreBlock := big.NewInt(0)
+		endNumberIfOneMoreBlock.Add(endNumber, blockSizeBig)
+
+		expectedNumberOfPrimes := uint64(primecount.EstimatePrimesBelow(endNumber)) + expectedNumberOfPrimesAdjustment
+		expectedNumberOfPrimesIfOneMoreBlock := uint64(primecount.EstimatePrimesBelow(endNumberIfOneMoreBlock)) + expectedNumberOfPrimesAdjustment
+
+		for targetNumberOfPrimes > expectedNumberOfPrimesIfOneMoreBlock+50_000 {
+			blocks++
+			endNumber.Set(endNumberIfOneMoreBlock)
+			expectedNumberOfPrimes = expectedNumberOfPrimesIfOneMoreBlock
+
+			endNumberIfOneMoreBlock.Add(endNumberIfOneMoreBlock, blockSizeBig)
+			expectedNumberOfPrimesIfOneMoreBlock = uint64(primecount.EstimatePrimesBelow(endNumberIfOneMoreBlock)) + expectedNumberOfPrimesAdjustment
+
+			if blocks >= parallelism {
+				break
+			}
+		}
+
+		if blocks < 2 {
+			break
+		}
+
+		if blocks > parallelism {
+			blocks = parallelism
+		}
+		log.Printf(
+			"Running %d blocks of size %d to go from %d primes to about %d",
+			blocks, blockSize, i.PrimesSoFar, expectedNumberOfPrimes,
+		)
+
+		channels := make([]chan uint64, blocks)
+		lastPrimeChannels := make([]chan big.Int, blocks)
+		primeLogChannels := make([]chan []primeGapEntry, blocks)
+		for j := 0; j < blocks; j++ {
+			channels[j] = make(chan uint64)
+			lastPrimeChannels[j] = make(chan big.Int)
+			primeLogChannels[j] = make(chan []primeGapEntry, 1)
+		}
+
+		finalInfoChannel := make(chan big.Int)
+
+		// blockBoundary[j] is a snapshot of the wheel/deterministically-primed
+		// number that block j starts at (== the number block j-1 ends at).
+		// We need our own copy because blockStartsAt gets overwritten in
+		// place as each block's goroutine runs (it doubles as myInfo.LastPrime).
+		blockBoundary := make([]big.Int, blocks)
+
+		for j := 0; j < blocks; j++ {
+			jthBlockStartsAt := big.NewInt(0)
+			jthBlockStartsAt.Add(i.CurrentNumber, big.NewInt(int64(blockSize)*int64(j)))
+
+			jthBlockEndsAt := big.NewInt(0)
+			jthBlockEndsAt.Add(jthBlockStartsAt, big.NewInt(int64(blockSize)))
+
+			incrementUntilDeterministicallyPrime(jthBlockStartsAt, i.PrecomputedPrimes)
+			incrementUntilDeterministicallyPrime(jthBlockEndsAt, i.PrecomputedPrimes)
+			blockBoundary[j].Set(jthBlockStartsAt)
+
+			go func(blockIndex int, blockStartsAt *big.Int, blockEndsAt *big.Int) {
+				myInfo := PrimeGapsInfo{
+					LastPrime:         blockStartsAt,
+					CurrentNumber:     big.NewInt(0).Add(blockStartsAt, big.NewInt(2)),
+					GapCounter:        make([]uint64, len(i.GapCounter)),
+					PrimesSoFar:       1,
+					MillerRabinBases:  i.MillerRabinBases,
+					PrecomputedPrimes: i.PrecomputedPrimes,
+				}
+
+				var primeLog []primeGapEntry
+				if i.Sink != nil {
+					myInfo.Sink = func(prime *big.Int, gap uint64) {
+						var cp big.Int
+						cp.Set(prime)
+						primeLog = append(primeLog, primeGapEntry{prime: cp, gap: gap})
+					}
+				}
+
+				myInfo.IterateToNumber(blockEndsAt)
+				for k := 0; k < len(myInfo.GapCounter); k++ {
+					channels[blockIndex] <- myInfo.GapCounter[k]
+				}
+				channels[blockIndex] <- math.MaxUint64
+				channels[blockIndex] <- myInfo.PrimesSoFar
+				lastPrimeChannels[blockIndex] <- *myInfo.LastPrime
+				primeLogChannels[blockIndex] <- primeLog
+
+				if blockIndex == blocks-1 {
+					finalInfoChannel <- *myInfo.LastPrime
+					finalInfoChannel <- *myInfo.CurrentNumber
+				}
+			}(j, jthBlockStartsAt, jthBlockEndsAt)
+		}
+
+		blockLastPrime := make([]big.Int, blocks)
+		blockPrimeLog := make([][]primeGapEntry, blocks)
+		for j := 0; j < blocks; j++ {
+			nextGapValue := <-channels[j]
+			for k := 0; nextGapValue != math.MaxUint64; k++ {
+				if k >= len(i.GapCounter) {
+					i.ExpandGapCounter()
+				}
+				i.GapCounter[k] += nextGapValue
+				nextGapValue = <-channels[j]
+			}
+			i.PrimesSoFar += <-channels[j]
+			blockLastPrime[j] = <-lastPrimeChannels[j]
+			blockPrimeLog[j] = <-primeLogChannels[j]
+		}
+
+		// Each block stops just short of its own blockEndsAt, which is
+		// either the next block's blockBoundary or (for the last block)
+		// this round's starting point for the next one; either way, the
+		// gap leading into that shared boundary prime from the previous
+		// block's (or round's) last real prime is never counted by either
+		// side's own loop. Stitch it back in here, once per seam.
+		seamGap := big.NewInt(0)
+		prevLastPrime := i.LastPrime
+		for j := 0; j < blocks; j++ {
+			seamGap.Sub(&blockBoundary[j], prevLastPrime)
+			seamGapIndex := int(seamGap.Uint64()) / 2
+			for seamGapIndex >= len(i.GapCounter) {
+				i.ExpandGapCounter()
+			}
+			i.GapCounter[seamGapIndex]++
+
+			if i.Sink != nil {
+				i.Sink(&blockBoundary[j], seamGap.Uint64())
+				for _, entry := range blockPrimeLog[j] {
+					i.Sink(&entry.prime, entry.gap)
+				}
+			}
+
+			prevLastPrime = &blockLastPrime[j]
+		}
+
+		i.LastPrime = big.NewInt(0)
+		i.CurrentNumber = big.NewInt(0)
+		*i.LastPrime = <-finalInfoChannel
+		*i.CurrentNumber = <-finalInfoChannel
+
+		log.Printf(
+			"After running blocks, now have %d primes (last prime: %s, current number: %s)",
+			i.PrimesSoFar, i.LastPrime.Text(10), i.CurrentNumber.Text(10),
+		)
+	}
+
+	log.Printf(
+		"Finding the remaining primes (at %d, want %d) serially",
+		i.PrimesSoFar, targetNumberOfPrimes,
+	)
+	i.IterateTo(targetNumberOfPrimes)
+}
+
+func (i *PrimeGapsInfo) ExpandGapCounter() {
+	newGapCounter := make([]uint64, len(i.GapCounter)*2)
+	for j := 0; j < len(i.GapCounter); j++ {
+		newGapCounter[j] = i.GapCounter[j]
+	}
+	i.GapCounter = newGapCounter
+}
+
+var precomputeWarning bool = false
+
+func deterministicIsPrime(n *big.Int, space []big.Int, precomputedPrimes *primebits.Set, zero *big.Int) bool {
+	(&space[0]).Sqrt(n) // space[0] = stopping point
+
+	isComposite := false
+	reachedStoppingPoint := false
+	precomputedPrimes.Iterate(func(p uint32) bool {
+		(&space[1]).SetUint64(uint64(p))                  // space[1] = current prime
+		if (&space[2]).Rem(n, &space[1]).Cmp(zero) == 0 { // (space[2] = (n % space[1])) == 0
+			isComposite = true
+			return false
+		}
+		if (&space[1]).Cmp(&space[0]) > 0 {
+			reachedStoppingPoint = true
+			return false
+		}
+		return true
+	})
+
+	if isComposite {
+		return false
+	}
+	if reachedStoppingPoint {
+		return true
+	}
+
+	// fallback, no more precomputed primes :(
+	if !precomputeWarning {
+		log.Printf("Ran out of precomputed primes checking if %s is prime deterministically", n.Text(10))
+		precomputeWarning = true
+	}
+
+	two := big.NewInt(2)
+	(&space[1]).SetUint64(uint64(precomputedPrimes.Max()))
+	for (&space[1]).Cmp(&space[0]) <= 0 {
+		(&space[1]).Add(&space[1], two)
+		if (&space[2]).Rem(n, &space[1]).Cmp(zero) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func incrementUntilDeterministicallyPrime(n *big.Int, precomputedPrimes *primebits.Set) {
+	zero := big.NewInt(0)
+	one := big.NewInt(1)
+	space := make([]big.Int, 3)
+
+	for !deterministicIsPrime(n, space, precomputedPrimes, zero) {
+		n.Add(n, one)
+	}
+}
+
+func getIntEnviron(envName string, def int) int {
+	envVal, found := os.LookupEnv(envName)
+	if !found {
+		log.Printf("Missing environment variable %s, assuming %d", envName, def)
+		return def
+	}
+
+	parsed, err := strconv.Atoi(envVal)
+	if err != nil {
+		log.Fatalf("Error interpreting environment variable %s: %s", envName, err)
+	}
+	return parsed
+}
+
+// loadInfo reads path, falling back to a fresh PrimeGapsInfo starting
+// at the first tracked prime (3) if it doesn't exist yet.
+func loadInfo(path string) *PrimeGapsInfo {
+	info := &PrimeGapsInfo{
+		LastPrime:        big.NewInt(3),
+		CurrentNumber:    big.NewInt(5),
+		GapCounter:       make([]uint64, 512),
+		PrimesSoFar:      1,
+		MillerRabinBases: 10,
+	}
+
+	marshalled, err := ioutil.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(marshalled, info); err != nil {
+			log.Fatalf("error unmarshalling %s: %s", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		log.Fatalf("error opening %s: %s", path, err)
+	}
+	return info
+}
+
+// saveInfo writes info to path as JSON, overwriting whatever's there.
+func saveInfo(path string, info *PrimeGapsInfo) {
+	marshalled, err := json.Marshal(info)
+	if err != nil {
+		log.Fatalf("error marshalling result: %s", err)
+	}
+	if err := ioutil.WriteFile(path, marshalled, 0644); err != nil {
+		log.Fatalf("error writing %s: %s", path, err)
+	}
+}
+
+// runExport implements the "export" subcommand: write one of the
+// OEIS-friendly formats in the export package from an info.json's
+// state, optionally extending that state first (-target) and/or
+// resuming the prime stream from an existing b-file (-resume-from)
+// rather than info.json alone.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	infoPath := fs.String("info", "info.json", "path to the info.json state file to export from")
+	outPath := fs.String("out", "", "output file path (default: stdout)")
+	resumeFrom := fs.String("resume-from", "", "path to an existing oeis-bfile to resume LastPrime/PrimesSoFar from")
+	target := fs.Uint64("target", 0, "if set, extend the run to this many primes before exporting (oeis-bfile/first-occurrence only)")
+	parallelism := fs.Int("parallelism", runtime.NumCPU(), "parallelism to use when -target is set")
+	blockSize := fs.Uint64("block-size", 1_000_000, "block size to use when -target is set")
+	precompute := fs.Int("precompute-primes", 1_000_000, "number of small primes to precompute when -target is set")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatalf("usage: export [oeis-bfile|first-occurrence|histogram-csv] [flags]")
+	}
+	format := fs.Arg(0)
+
+	info := loadInfo(*infoPath)
+
+	if *resumeFrom != "" {
+		// first-occurrence's "seen" map starts empty on every invocation,
+		// so resuming it would wrongly report gaps that recurred in the
+		// new segment, but genuinely first occurred before the resume
+		// point, as first occurrences. oeis-bfile has no such state to
+		// get out of sync, so it's the only format -resume-from is sound
+		// for.
+		if format != "oeis-bfile" {
+			log.Fatalf("-resume-from is only supported for oeis-bfile; first-occurrence has no way to recover gap sizes seen before the resume point")
+		}
+
+		f, err := os.Open(*resumeFrom)
+		if err != nil {
+			log.Fatalf("error opening %s: %s", *resumeFrom, err)
+		}
+		lastPrime, primesSoFar, err := export.ResumeFromBFile(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("error resuming from %s: %s", *resumeFrom, err)
+		}
+		info.LastPrime = lastPrime
+		info.PrimesSoFar = primesSoFar
+		info.CurrentNumber = new(big.Int).Add(lastPrime, big.NewInt(2))
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		// When -out is the same file we just resumed from, this is the
+		// checkpoint-and-append workflow the -resume-from flag exists
+		// for: open in append mode so the rows already on disk survive,
+		// instead of truncating them via os.Create.
+		var f *os.File
+		var err error
+		if *resumeFrom != "" && *outPath == *resumeFrom {
+			f, err = os.OpenFile(*outPath, os.O_WRONLY|os.O_APPEND, 0644)
+		} else {
+			f, err = os.Create(*outPath)
+		}
+		if err != nil {
+			log.Fatalf("error opening %s: %s", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch format {
+	case "oeis-bfile":
+		sink, flush := export.BFileSink(out, info.PrimesSoFar-1)
+		info.Sink = sink
+		if *target > 0 {
+			info.PrecomputePrimes(*precompute)
+			info.IterateToParallel(*target, *parallelism, *blockSize)
+		}
+		if err := flush(); err != nil {
+			log.Fatalf("error writing %s: %s", *outPath, err)
+		}
+	case "first-occurrence":
+		sink, results := export.FirstOccurrence(info.LastPrime)
+		info.Sink = sink
+		if *target > 0 {
+			info.PrecomputePrimes(*precompute)
+			info.IterateToParallel(*target, *parallelism, *blockSize)
+		}
+		if err := export.WriteFirstOccurrenceCSV(out, results()); err != nil {
+			log.Fatalf("error writing %s: %s", *outPath, err)
+		}
+	case "histogram-csv":
+		if err := export.WriteHistogramCSV(out, info.GapCounter); err != nil {
+			log.Fatalf("error writing %s: %s", *outPath, err)
+		}
+	default:
+		log.Fatalf("unknown export format %q", format)
+	}
+
+	saveInfo(*infoPath, info)
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
+	info := loadInfo("info.json")
+
+	targetNumberOfPrimesToPrecompute := getIntEnviron("PRECOMPUTE_PRIMES", 1_000_000)
+	targetNumberOfPrimesForPlot := getIntEnviron("TARGET_PRIMES", int(info.PrimesSoFar)+10_000_000)
+	parallelism := getIntEnviron("PARALLELISM", runtime.NumCPU())
+	blockSize := getIntEnviron("BLOCK_SIZE", 1_000_000)
+
+	info.PrecomputePrimes(targetNumberOfPrimesToPrecompute)
+
+	log.Printf("Continuing from %d primes...", info.PrimesSoFar)
+	info.IterateToParallel(uint64(targetNumberOfPrimesForPlot), parallelism, uint64(blockSize))
+	log.Printf("Now at %d primes", info.PrimesSoFar)
+
+	saveInfo("info.json", info)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+// naiveGapHistogram computes a prime gap histogram by stepping by two
+// from 3 and testing each candidate with big.Int.ProbablyPrime, the way
+// this codebase did before the mod-210 wheel (see the wheel package)
+// and segmented sieve replaced it. It's an independent reference for
+// TestWheelMatchesStepByTwoHistogram.
+func naiveGapHistogram(targetPrimes uint64) []uint64 {
+	hist := make([]uint64, 64)
+	last := big.NewInt(3)
+	current := big.NewInt(5)
+	two := big.NewInt(2)
+	found := uint64(1)
+
+	for found < targetPrimes {
+		if current.ProbablyPrime(20) {
+			gap := new(big.Int).Sub(current, last)
+			idx := int(gap.Uint64()) / 2
+			for idx >= len(hist) {
+				hist = append(hist, make([]uint64, len(hist))...)
+			}
+			hist[idx]++
+			last.Set(current)
+			found++
+		}
+		current.Add(current, two)
+	}
+	return hist
+}
+
+// TestWheelMatchesStepByTwoHistogram checks that IterateTo's mod-210
+// wheel stepping produces the same gap histogram as plain step-by-two
+// ProbablyPrime testing. It runs over the first 20,000 primes rather than the
+// 10^7 this behavior was originally checked against by hand, since the
+// naive reference here is too slow for that range to be a practical
+// regression test; a wheel/step-by-two divergence would show up near
+// the wheel's floor, well within this smaller range.
+func TestWheelMatchesStepByTwoHistogram(t *testing.T) {
+	const targetPrimes = 20000
+
+	naive := naiveGapHistogram(targetPrimes)
+
+	wheeled := &PrimeGapsInfo{
+		LastPrime:        big.NewInt(3),
+		CurrentNumber:    big.NewInt(5),
+		GapCounter:       make([]uint64, 64),
+		PrimesSoFar:      1,
+		MillerRabinBases: 20,
+	}
+	wheeled.IterateTo(targetPrimes)
+
+	maxLen := len(naive)
+	if len(wheeled.GapCounter) > maxLen {
+		maxLen = len(wheeled.GapCounter)
+	}
+	for idx := 0; idx < maxLen; idx++ {
+		var naiveCount, wheeledCount uint64
+		if idx < len(naive) {
+			naiveCount = naive[idx]
+		}
+		if idx < len(wheeled.GapCounter) {
+			wheeledCount = wheeled.GapCounter[idx]
+		}
+		if naiveCount != wheeledCount {
+			t.Fatalf("gap %d: naive=%d wheeled=%d", idx*2, naiveCount, wheeledCount)
+		}
+	}
+}
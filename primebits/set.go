@@ -0,0 +1,290 @@
+// Package primebits implements a compact, bit-packed set of small
+// primes, one bit per odd number, used in place of a []uint32 list
+// wherever only primality membership and in-order iteration matter. A
+// []uint32 list of the first 10 million primes costs ~40MB; a Set
+// covering the same range (up to the 10-millionth prime, ~1.8*10^8)
+// costs ~11MB regardless of how many of those numbers turn out to be
+// prime, and is sieved directly rather than built one
+// big.Int.ProbablyPrime call at a time.
+package primebits
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/bits"
+	"sync"
+)
+
+// BasePrimes is implemented by anything that can enumerate a table of
+// small primes in increasing order, e.g. to seed a segmented sieve or
+// drive the sqrt-bound trial division in deterministicIsPrime. *Set
+// satisfies it.
+type BasePrimes interface {
+	Iterate(f func(p uint32) bool)
+	Max() uint32
+}
+
+// Set is a bit-packed set of the primes in [2, Bound()), safe for
+// concurrent use since IterateToParallel hands the same *Set to every
+// block goroutine.
+type Set struct {
+	mu    sync.RWMutex
+	data  []uint64 // bit i means the odd number 2*i+3 is prime
+	bound uint32   // the set covers odd numbers in [3, bound)
+	max   uint32   // the largest prime currently recorded
+}
+
+// NewSet returns an empty Set, covering no primes yet. Call GrowTo to
+// sieve it up to some bound.
+func NewSet() *Set {
+	return &Set{}
+}
+
+func bitIndex(p uint32) uint32 { return (p - 3) / 2 }
+
+// numOddBitsBelow returns how many odd numbers lie in [3, bound),
+// which must be odd itself (every Set normalizes its bound to odd).
+func numOddBitsBelow(bound uint32) uint32 {
+	if bound <= 3 {
+		return 0
+	}
+	return (bound - 3) / 2
+}
+
+// HasPrime reports whether p is marked prime in the set. p must be
+// below Bound() for the answer to be meaningful.
+func (s *Set) HasPrime(p uint32) bool {
+	if s == nil {
+		return false
+	}
+	if p == 2 {
+		return true
+	}
+	if p < 3 || p%2 == 0 || p >= s.Bound() {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx := bitIndex(p)
+	return s.data[idx/64]&(1<<(idx%64)) != 0
+}
+
+// Bound returns the exclusive upper bound the set currently covers.
+func (s *Set) Bound() uint32 {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bound
+}
+
+// Max returns the largest prime currently recorded in the set, or 2 if
+// the set is still empty.
+func (s *Set) Max() uint32 {
+	if s == nil {
+		return 2
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.max == 0 {
+		return 2
+	}
+	return s.max
+}
+
+// Count returns the number of primes currently recorded in the set.
+func (s *Set) Count() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	if s.bound > 2 {
+		n = 1 // 2 itself
+	}
+	for _, w := range s.data {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// NextPrimeAfter returns the smallest recorded prime strictly greater
+// than p, or 0 if the set doesn't know about one yet (p >= Max()). It
+// walks whole words at a time via bits.TrailingZeros64 rather than
+// testing bit by bit.
+func (s *Set) NextPrimeAfter(p uint32) uint32 {
+	if s == nil {
+		return 0
+	}
+	if p < 2 {
+		return 2
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := bitIndex(p) + 1
+	if p < 3 {
+		start = 0
+	}
+	numBits := numOddBitsBelow(s.bound)
+
+	mask := ^uint64(0) << (start % 64)
+	for word := start / 64; word < uint32(len(s.data)); word++ {
+		w := s.data[word] & mask
+		mask = ^uint64(0)
+		if w != 0 {
+			bit := word*64 + uint32(bits.TrailingZeros64(w))
+			if bit >= numBits {
+				return 0
+			}
+			return bit*2 + 3
+		}
+	}
+	return 0
+}
+
+// iterate calls f once per prime recorded in data (covering odd
+// numbers below bound), in increasing order starting with 2, stopping
+// early if f returns false. It's the lock-free core shared by Iterate
+// and GrowTo, which already holds the write lock.
+func iterate(data []uint64, bound uint32, f func(p uint32) bool) {
+	if bound == 0 {
+		return
+	}
+	if !f(2) {
+		return
+	}
+	for word := 0; word < len(data); word++ {
+		w := data[word]
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			w &^= 1 << bit
+			p := uint32(word*64+bit)*2 + 3
+			if p >= bound {
+				return
+			}
+			if !f(p) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate calls f once per prime in the set, in increasing order
+// starting with 2, stopping early if f returns false.
+func (s *Set) Iterate(f func(p uint32) bool) {
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	iterate(s.data, s.bound, f)
+}
+
+// GrowTo extends the set, by sieving, so that it covers odd numbers up
+// to at least bound. Unlike the []uint32 precompute this replaces, it
+// never calls big.Int.ProbablyPrime: it re-sieves from scratch with a
+// plain Sieve of Eratosthenes, which is cheap enough that there's no
+// need for the added complexity of extending the old bitmap in place.
+// A no-op if the set already covers bound.
+func (s *Set) GrowTo(bound uint32) {
+	if bound%2 == 0 {
+		bound++
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if bound <= s.bound {
+		return
+	}
+
+	numBits := numOddBitsBelow(bound)
+	numWords := (numBits + 63) / 64
+	data := make([]uint64, numWords)
+	for i := range data {
+		data[i] = ^uint64(0) // assume prime; composites cleared below
+	}
+	if trailing := numWords*64 - numBits; trailing > 0 {
+		data[numWords-1] &^= ^uint64(0) << (64 - trailing)
+	}
+
+	for p := uint32(3); uint64(p)*uint64(p) < uint64(bound); p += 2 {
+		idx := bitIndex(p)
+		if data[idx/64]&(1<<(idx%64)) == 0 {
+			continue // p was itself sieved out by a smaller prime
+		}
+		for m := uint64(p) * uint64(p); m < uint64(bound); m += 2 * uint64(p) {
+			mi := bitIndex(uint32(m))
+			data[mi/64] &^= 1 << (mi % 64)
+		}
+	}
+
+	max := uint32(2)
+	for bit := int(numBits) - 1; bit >= 0; bit-- {
+		if data[bit/64]&(1<<(uint(bit)%64)) != 0 {
+			max = uint32(bit)*2 + 3
+			break
+		}
+	}
+
+	s.data = data
+	s.bound = bound
+	s.max = max
+}
+
+// jsonSet is Set's on-disk shape: the bitmap as base64 alongside the
+// bit length needed to decode it, since encoding/json has no native
+// binary type.
+type jsonSet struct {
+	Bound uint32 `json:"bound"`
+	Max   uint32 `json:"max"`
+	Bits  string `json:"bits"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Set) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	raw := make([]byte, len(s.data)*8)
+	for i, w := range s.data {
+		binary.LittleEndian.PutUint64(raw[i*8:], w)
+	}
+
+	return json.Marshal(jsonSet{
+		Bound: s.bound,
+		Max:   s.max,
+		Bits:  base64.StdEncoding.EncodeToString(raw),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Set) UnmarshalJSON(b []byte) error {
+	var js jsonSet
+	if err := json.Unmarshal(b, &js); err != nil {
+		return err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(js.Bits)
+	if err != nil {
+		return err
+	}
+
+	data := make([]uint64, len(raw)/8)
+	for i := range data {
+		data[i] = binary.LittleEndian.Uint64(raw[i*8:])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	s.bound = js.Bound
+	s.max = js.Max
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package primecount estimates how many primes lie below a given bound,
+// for scheduling decisions (e.g. IterateToParallel's block count) that
+// only need to be close, not exact. It replaces the crude Prime Number
+// Theorem approximation x/ln(x) with the logarithmic integral Li(x),
+// which tracks the true prime-counting function pi(x) far more closely,
+// especially for the large x this codebase deals with.
+package primecount
+
+import (
+	"math"
+	"math/big"
+)
+
+// eulerMascheroni is gamma, the constant term in the Li(x) series below.
+const eulerMascheroni = 0.5772156649015328606065120900824024310421593359399235988057672348848677
+
+// maxSeriesTerms caps the Li(x) series in case logX is large enough that
+// the 1e-12 convergence check would otherwise take an unreasonable
+// number of terms; it converges in about 100 terms at x = 2^64 and
+// about 900 terms near x = 2^1024.
+const maxSeriesTerms = 1000
+
+// EstimatePrimesBelow estimates the number of primes less than n using
+// the logarithmic integral
+//
+//	Li(x) = gamma + ln(ln(x)) + sum_{k=1}^inf (ln x)^k / (k * k!)
+//
+// n is converted to ln(x) via big.Float's mantissa/exponent rather than
+// to x itself, so n may exceed what fits in a float64 (the float64 range
+// tops out just under 2^1024, but ln(x) for such an x is only around
+// 710). The estimate itself is still a float64: it is +Inf once Li(n)
+// no longer fits, from about n = 2^1034.
+func EstimatePrimesBelow(n *big.Int) float64 {
+	if n.Sign() <= 0 || n.Cmp(big.NewInt(2)) < 0 {
+		return 0
+	}
+	return li(lnOf(n))
+}
+
+// EstimatePrimesBelowR estimates the number of primes less than n using
+// Riemann's prime-counting function
+//
+//	R(x) = sum_{k=1}^inf mu(k)/k * Li(x^(1/k))
+//
+// which converges to pi(x) faster than Li(x) alone. The sum is
+// truncated once x^(1/k) drops below 2, where Li stops being meaningful.
+func EstimatePrimesBelowR(n *big.Int) float64 {
+	if n.Sign() <= 0 || n.Cmp(big.NewInt(2)) < 0 {
+		return 0
+	}
+
+	lnX := lnOf(n)
+	sum := 0.0
+	for k := 1; k <= maxSeriesTerms; k++ {
+		lnXK := lnX / float64(k)
+		if lnXK < math.Ln2 {
+			break
+		}
+		if mu := mobius(k); mu != 0 {
+			sum += float64(mu) / float64(k) * li(lnXK)
+		}
+	}
+	return sum
+}
+
+// lnOf returns ln(n) as a float64, via big.Float's mantissa/exponent
+// split (n = mantissa * 2^exp, mantissa in [0.5, 1)) rather than
+// converting n itself to a float64 first, which would overflow well
+// before n does.
+func lnOf(n *big.Int) float64 {
+	mantissa := new(big.Float)
+	exp := new(big.Float).SetInt(n).MantExp(mantissa)
+	m, _ := mantissa.Float64()
+	return math.Log(m) + float64(exp)*math.Ln2
+}
+
+// li evaluates the Li(x) series given ln(x) directly, terminating once a
+// term contributes less than 1e-12 of the running sum.
+func li(lnX float64) float64 {
+	if lnX <= 0 {
+		return 0
+	}
+
+	sum := 0.0
+	term := 1.0 // (ln x)^0 / 0!
+	for k := 1; k <= maxSeriesTerms; k++ {
+		term *= lnX / float64(k)
+		contribution := term / float64(k)
+		sum += contribution
+		if math.Abs(contribution) < 1e-12*math.Abs(sum) {
+			break
+		}
+	}
+	return eulerMascheroni + math.Log(lnX) + sum
+}
+
+// mobius returns the Mobius function mu(k) for small k: 1 if k is
+// squarefree with an even number of prime factors, -1 if squarefree
+// with an odd number, 0 if any prime factor repeats. k is always small
+// here (EstimatePrimesBelowR stops once ln(x)/k < ln(2)), so trial
+// division is plenty fast.
+func mobius(k int) int {
+	if k == 1 {
+		return 1
+	}
+
+	result := 1
+	remaining := k
+	for p := 2; p*p <= remaining; p++ {
+		if remaining%p != 0 {
+			continue
+		}
+		remaining /= p
+		if remaining%p == 0 {
+			return 0
+		}
+		result = -result
+	}
+	if remaining > 1 {
+		result = -result
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package primecount
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// piCheckpoints pairs x with the true prime-counting function pi(x), at
+// the checkpoints EstimatePrimesBelow is expected to be accurate at.
+var piCheckpoints = []struct {
+	x  int64
+	pi float64
+}{
+	{1_000_000, 78498},
+	{100_000_000, 5_761_455},
+	{10_000_000_000, 455_052_511},
+}
+
+func TestEstimatePrimesBelowWithin1Percent(t *testing.T) {
+	for _, c := range piCheckpoints {
+		got := EstimatePrimesBelow(big.NewInt(c.x))
+		relErr := math.Abs(got-c.pi) / c.pi
+		if relErr > 0.01 {
+			t.Errorf("EstimatePrimesBelow(%d) = %f, want within 1%% of pi(x)=%f (off by %.3f%%)",
+				c.x, got, c.pi, relErr*100)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package sieve implements a segmented Sieve of Eratosthenes for
+// streaming primes in increasing order, used as a fast replacement for
+// trial-division primality checks when the candidates fit in a uint64.
+package sieve
+
+import (
+	"math/big"
+
+	"github.com/Tjstretchalot/prime-gaps/primebits"
+)
+
+// DefaultWidth is the default number of odd integers covered by each
+// sieved segment. It's sized to keep the segment bitmap (Width/8 bytes)
+// comfortably within L2 cache; callers that know their cache size can
+// tune this via NewSegmentedSieve.
+const DefaultWidth uint64 = 1 << 20
+
+// SegmentedSieve streams primes, in increasing order, starting at or
+// above the number it was constructed with. It sieves fixed-size
+// windows of odd integers using a table of base primes, growing that
+// table (via primebits.Set.GrowTo) whenever a segment needs primes
+// larger than any known so far. It never falls back to trial division
+// against the candidates themselves.
+type SegmentedSieve struct {
+	// Width is the number of odd integers covered by each segment.
+	Width uint64
+
+	basePrimes *primebits.Set
+
+	segLo  uint64 // first number (odd) covered by bitmap
+	bitmap []uint64
+	idx    uint64 // next bit to inspect within bitmap
+
+	primed bool // whether bitmap holds a sieved segment yet
+}
+
+// isqrt returns floor(sqrt(n)), computed via big.Int.Sqrt rather than
+// math.Sqrt so it stays exact for n up to 2^64, not just within
+// float64's 53 bits of precision.
+func isqrt(n uint64) uint64 {
+	return new(big.Int).Sqrt(new(big.Int).SetUint64(n)).Uint64()
+}
+
+// NewSegmentedSieve creates a sieve that yields primes starting at or
+// above start. basePrimes seeds the table of base primes used to sieve
+// each segment; it is grown automatically (in place) as larger
+// segments require larger base primes, so a fresh, empty Set works,
+// it's just slower to start. If basePrimes is nil, a fresh Set is
+// created. If width is 0, DefaultWidth is used.
+func NewSegmentedSieve(start uint64, basePrimes *primebits.Set, width uint64) *SegmentedSieve {
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if basePrimes == nil {
+		basePrimes = primebits.NewSet()
+	}
+	if start < 2 {
+		start = 2
+	}
+
+	s := &SegmentedSieve{
+		Width:      width,
+		basePrimes: basePrimes,
+		segLo:      start,
+	}
+	if s.segLo%2 == 0 {
+		s.segLo++ // segments only ever cover odd numbers; 2 is handled by the caller
+	}
+	return s
+}
+
+// BasePrimes returns the table of base primes backing this sieve,
+// including any primes discovered while sieving.
+func (s *SegmentedSieve) BasePrimes() *primebits.Set {
+	return s.basePrimes
+}
+
+// Next returns the next prime at or above the sieve's current
+// position. It sieves new segments as needed and never returns a
+// number smaller than one previously returned.
+func (s *SegmentedSieve) Next() uint64 {
+	for {
+		if !s.primed || s.idx >= s.Width {
+			s.sieveNextSegment()
+		}
+
+		for s.idx < s.Width {
+			bit := s.idx
+			s.idx++
+			if s.bitmap[bit/64]&(1<<(bit%64)) == 0 {
+				return s.segLo + bit*2
+			}
+		}
+	}
+}
+
+// sieveNextSegment fills bitmap with the compositeness of the odd
+// numbers in [segLo, segLo+2*Width), growing basePrimes first if the
+// segment needs base primes larger than any currently known. Only base
+// primes p with p*p < segHi are ever consulted (see the Iterate loop
+// below), so basePrimes only needs to cover roughly sqrt(segHi), not
+// segHi itself.
+func (s *SegmentedSieve) sieveNextSegment() {
+	if s.primed {
+		s.segLo += 2 * s.Width
+	}
+	segHi := s.segLo + 2*s.Width
+
+	sqrtBound := isqrt(segHi) + 1
+	if sqrtBound > uint64(^uint32(0)) {
+		// base primes are capped at uint32; nothing sieves candidates
+		// this large with base primes this small anyway (p*p would have
+		// overflowed uint32 long before segHi gets here), so just grow
+		// as far as the table can go.
+		sqrtBound = uint64(^uint32(0))
+	}
+	s.basePrimes.GrowTo(uint32(sqrtBound))
+
+	words := (s.Width + 63) / 64
+	if uint64(len(s.bitmap)) != words {
+		s.bitmap = make([]uint64, words)
+	} else {
+		for j := range s.bitmap {
+			s.bitmap[j] = 0
+		}
+	}
+
+	s.basePrimes.Iterate(func(bp uint32) bool {
+		p := uint64(bp)
+		if p < 3 {
+			return true // 2 never divides an odd candidate
+		}
+		if p*p >= segHi {
+			return false
+		}
+
+		var start uint64
+		if p*p >= s.segLo {
+			start = p * p
+		} else {
+			start = s.segLo + (p-s.segLo%p)%p
+			if start%2 == 0 {
+				start += p
+			}
+		}
+
+		for m := start; m < segHi; m += 2 * p {
+			bit := (m - s.segLo) / 2
+			s.bitmap[bit/64] |= 1 << (bit % 64)
+		}
+		return true
+	})
+
+	s.idx = 0
+	s.primed = true
+}
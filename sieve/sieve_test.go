@@ -0,0 +1,52 @@
+package sieve
+
+import "testing"
+
+// naiveIsPrime reports primality by trial division, independent of
+// SegmentedSieve, as a reference for TestSegmentedSieveMatchesTrialDivision.
+func naiveIsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	if n%2 == 0 {
+		return n == 2
+	}
+	for d := uint64(3); d*d <= n; d += 2 {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSegmentedSieveMatchesTrialDivision checks that SegmentedSieve,
+// started at a few different points (including an even start and one
+// that is not a multiple of the segment width, to exercise the
+// odd-numbers-only indexing in sieveNextSegment), yields
+// exactly the odd primes trial division would. SegmentedSieve only
+// ever covers odd numbers (2 is the caller's responsibility, see
+// sieveDeterministically in main.go), so 2 is excluded from want.
+func TestSegmentedSieveMatchesTrialDivision(t *testing.T) {
+	for _, start := range []uint64{0, 2, 1_000_003} {
+		limit := start + 100_000
+
+		var want []uint64
+		for n := start; n < limit; n++ {
+			if n > 2 && naiveIsPrime(n) {
+				want = append(want, n)
+			}
+		}
+
+		s := NewSegmentedSieve(start, nil, 1<<12) // small width to force several segments
+		var got []uint64
+		for len(got) < len(want) {
+			got = append(got, s.Next())
+		}
+
+		for idx := range want {
+			if got[idx] != want[idx] {
+				t.Fatalf("start=%d: prime #%d: got %d, want %d", start, idx, got[idx], want[idx])
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package wheel generates prime candidates using a mod-210 wheel: every
+// prime greater than 7 is congruent, mod 210, to one of 48 residues
+// coprime to 2, 3, 5, and 7. Stepping a candidate through those
+// residues skips 57 of every 105 odd numbers (about 54%; about 77% of
+// all integers) before they ever reach a primality test.
+package wheel
+
+import "math/big"
+
+// Modulus is the wheel's period: 2 * 3 * 5 * 7.
+const Modulus = 210
+
+// Residues are the 48 numbers in [0, Modulus) coprime to 2, 3, 5, and
+// 7, in increasing order. Every prime above 7 is congruent to exactly
+// one of these, mod Modulus; numbers below 11 (namely 2, 3, 5, 7
+// themselves) are not representable on the wheel and must be handled
+// separately by callers.
+var Residues = [48]uint64{
+	1, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67,
+	71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 121, 127, 131,
+	137, 139, 143, 149, 151, 157, 163, 167, 169, 173, 179, 181, 187,
+	191, 193, 197, 199, 209,
+}
+
+// Deltas[i] is the distance from Residues[i] to the next residue in
+// the wheel, wrapping the last residue around to the first plus a full
+// Modulus (e.g. 209 -> 211 is a delta of 2).
+var Deltas = computeDeltas()
+
+// DeltasBig mirrors Deltas as *big.Int, precomputed once since Advance
+// is called once per candidate and allocating a big.Int per call would
+// defeat the point of skipping candidates cheaply.
+var DeltasBig = computeDeltasBig()
+
+func computeDeltas() [48]uint64 {
+	var d [48]uint64
+	for i := range Residues {
+		if i == len(Residues)-1 {
+			d[i] = Modulus + Residues[0] - Residues[i]
+		} else {
+			d[i] = Residues[i+1] - Residues[i]
+		}
+	}
+	return d
+}
+
+func computeDeltasBig() [48]*big.Int {
+	var d [48]*big.Int
+	for i, v := range Deltas {
+		d[i] = big.NewInt(int64(v))
+	}
+	return d
+}
+
+// Iterator tracks the position within Residues/Deltas for a candidate
+// that's being advanced along the wheel. It holds no reference to the
+// candidate itself; callers pass it to Advance each time, the same way
+// the rest of this codebase advances a *big.Int by reference (e.g.
+// CurrentNumber.Add(CurrentNumber, two)).
+type Iterator struct {
+	idx int
+}
+
+// NewIterator snaps cand up, in place, to the smallest wheel residue
+// that is >= cand's current value, and returns an Iterator positioned
+// there. cand must be at least 11; the wheel cannot represent 2, 3, 5,
+// or 7, since those are the primes it's built from.
+func NewIterator(cand *big.Int) *Iterator {
+	modulus := big.NewInt(Modulus)
+	base := new(big.Int)
+	rem := new(big.Int)
+	base.DivMod(cand, modulus, rem)
+	base.Mul(base, modulus) // base = largest multiple of Modulus <= cand
+
+	r := rem.Uint64()
+	idx := 0
+	for idx < len(Residues) && Residues[idx] < r {
+		idx++
+	}
+	if idx == len(Residues) {
+		idx = 0
+		base.Add(base, modulus)
+	}
+
+	cand.Add(base, new(big.Int).SetUint64(Residues[idx]))
+	return &Iterator{idx: idx}
+}
+
+// Advance moves cand, in place, to the next wheel-aligned candidate.
+func (it *Iterator) Advance(cand *big.Int) {
+	cand.Add(cand, DeltasBig[it.idx])
+	it.idx = (it.idx + 1) % len(Residues)
+}